@@ -0,0 +1,162 @@
+// Package collector polls gopsutil for CPU, memory, disk, and network
+// metrics and produces a single Snapshot per call. It is shared by the
+// Bubble Tea dashboard, the -headless JSON loop, and the -export HTTP
+// server so all three paths sample metrics the same way.
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// DiskStat is one partition's usage and I/O throughput at the time of a
+// Snapshot. Read/WriteBytes are in bytes/sec, averaged over the interval
+// since the previous Snapshot (zero on the first sample for a mountpoint).
+type DiskStat struct {
+	Mountpoint  string  `json:"mountpoint"`
+	UsedPercent float64 `json:"used_percent"`
+	ReadBytes   float64 `json:"read_bytes_per_sec"`
+	WriteBytes  float64 `json:"write_bytes_per_sec"`
+}
+
+// NetStat is one interface's throughput, in bytes/sec, averaged over the
+// interval since the previous Snapshot.
+type NetStat struct {
+	Name      string  `json:"name"`
+	RecvBytes float64 `json:"recv_bytes_per_sec"`
+	SentBytes float64 `json:"sent_bytes_per_sec"`
+}
+
+// Snapshot is one sample across all tracked metrics.
+type Snapshot struct {
+	Timestamp  time.Time  `json:"timestamp"`
+	CPUTotal   float64    `json:"cpu_total_percent"`
+	CPUPerCore []float64  `json:"cpu_per_core_percent"`
+	MemPercent float64    `json:"mem_used_percent"`
+	Disks      []DiskStat `json:"disks"`
+	Nets       []NetStat  `json:"nets"`
+}
+
+type netSample struct {
+	bytesRecv uint64
+	bytesSent uint64
+	at        time.Time
+}
+
+type diskSample struct {
+	readBytes  uint64
+	writeBytes uint64
+	at         time.Time
+}
+
+// Collector holds the state needed to derive per-tick deltas (the previous
+// disk and network counters) across repeated Collect calls.
+type Collector struct {
+	diskPrev map[string]diskSample
+	netPrev  map[string]netSample
+}
+
+// New returns a Collector ready for its first Collect call.
+func New() *Collector {
+	return &Collector{
+		diskPrev: make(map[string]diskSample),
+		netPrev:  make(map[string]netSample),
+	}
+}
+
+// Collect polls every metric source once and returns the resulting
+// Snapshot. Partial failures (e.g. unreadable network counters) are
+// reported as errs but do not prevent the rest of the Snapshot from being
+// filled in. A partition whose usage cannot be read is left out of Disks
+// without an error.
+func (c *Collector) Collect() (Snapshot, []error) {
+	var errs []error
+	snap := Snapshot{Timestamp: time.Now()}
+
+	if totals, err := cpu.Percent(0, false); err != nil {
+		errs = append(errs, fmt.Errorf("cpu total: %w", err))
+	} else if len(totals) > 0 {
+		snap.CPUTotal = totals[0]
+	}
+
+	if perCore, err := cpu.Percent(0, true); err != nil {
+		errs = append(errs, fmt.Errorf("cpu per-core: %w", err))
+	} else {
+		snap.CPUPerCore = perCore
+	}
+
+	if vm, err := mem.VirtualMemory(); err != nil {
+		errs = append(errs, fmt.Errorf("mem: %w", err))
+	} else {
+		snap.MemPercent = vm.UsedPercent
+	}
+
+	partitions, err := disk.Partitions(false)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("disk partitions: %w", err))
+	}
+
+	ioCounters, err := disk.IOCounters()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("disk io: %w", err))
+	}
+
+	for _, p := range partitions {
+		usage, err := disk.Usage(p.Mountpoint)
+		if err != nil {
+			continue
+		}
+		stat := DiskStat{Mountpoint: p.Mountpoint, UsedPercent: usage.UsedPercent}
+
+		if cnt, ok := ioCounters[diskDeviceName(p.Device)]; ok {
+			prev, had := c.diskPrev[p.Mountpoint]
+			c.diskPrev[p.Mountpoint] = diskSample{readBytes: cnt.ReadBytes, writeBytes: cnt.WriteBytes, at: snap.Timestamp}
+			if had {
+				if elapsed := snap.Timestamp.Sub(prev.at).Seconds(); elapsed > 0 {
+					stat.ReadBytes = float64(cnt.ReadBytes-prev.readBytes) / elapsed
+					stat.WriteBytes = float64(cnt.WriteBytes-prev.writeBytes) / elapsed
+				}
+			}
+		}
+
+		snap.Disks = append(snap.Disks, stat)
+	}
+	sort.Slice(snap.Disks, func(i, j int) bool { return snap.Disks[i].Mountpoint < snap.Disks[j].Mountpoint })
+
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("net: %w", err))
+	}
+	for _, cnt := range counters {
+		prev, had := c.netPrev[cnt.Name]
+		c.netPrev[cnt.Name] = netSample{bytesRecv: cnt.BytesRecv, bytesSent: cnt.BytesSent, at: snap.Timestamp}
+		if !had {
+			continue
+		}
+		elapsed := snap.Timestamp.Sub(prev.at).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+		snap.Nets = append(snap.Nets, NetStat{
+			Name:      cnt.Name,
+			RecvBytes: float64(cnt.BytesRecv-prev.bytesRecv) / elapsed,
+			SentBytes: float64(cnt.BytesSent-prev.bytesSent) / elapsed,
+		})
+	}
+	sort.Slice(snap.Nets, func(i, j int) bool { return snap.Nets[i].Name < snap.Nets[j].Name })
+
+	return snap, errs
+}
+
+// diskDeviceName strips the "/dev/" prefix disk.Partitions reports so it
+// matches the bare device names disk.IOCounters keys its map by.
+func diskDeviceName(device string) string {
+	return strings.TrimPrefix(device, "/dev/")
+}
@@ -0,0 +1,108 @@
+// Package config loads and writes the dashboard's TOML configuration file,
+// which controls widget selection/order, refresh interval, color palette,
+// alert thresholds, and locale.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Colors holds the lipgloss color strings used to paint the dashboard.
+type Colors struct {
+	GradientStart string `toml:"gradient_start"`
+	GradientEnd   string `toml:"gradient_end"`
+	Error         string `toml:"error"`
+	Label         string `toml:"label"`
+}
+
+// Thresholds holds the values that drive threshold-crossing alerts.
+type Thresholds struct {
+	CPUAlertPercent float64 `toml:"cpu_alert_percent"`
+	CPUAlertSeconds int     `toml:"cpu_alert_seconds"`
+}
+
+// Config is the full set of user-tunable dashboard settings.
+type Config struct {
+	Locale          string        `toml:"locale"`
+	RefreshInterval time.Duration `toml:"refresh_interval"`
+	Widgets         []string      `toml:"widgets"`
+	Colors          Colors        `toml:"colors"`
+	Thresholds      Thresholds    `toml:"thresholds"`
+}
+
+// Default returns the built-in configuration used when no config file is
+// present, and as the base that a partial file is merged over.
+func Default() Config {
+	return Config{
+		Locale:          "en_US",
+		RefreshInterval: time.Second,
+		Widgets:         []string{"cpu", "mem", "disk", "net", "proc"},
+		Colors: Colors{
+			GradientStart: "#5A56E0",
+			GradientEnd:   "#EE6FF8",
+			Error:         "9",
+			Label:         "",
+		},
+		Thresholds: Thresholds{
+			CPUAlertPercent: 90,
+			CPUAlertSeconds: 5,
+		},
+	}
+}
+
+// Path returns the config file location, searched at
+// $XDG_CONFIG_HOME/tui/config.toml (falling back to ~/.config when
+// XDG_CONFIG_HOME is unset, per the XDG base directory spec).
+func Path() (string, error) {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolve home directory: %w", err)
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "tui", "config.toml"), nil
+}
+
+// Load reads the config file at Path, merging its values over Default. A
+// missing file is not an error; Default is returned unchanged.
+func Load() (Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cfg, nil
+	}
+
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return cfg, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Write encodes cfg as TOML to path, creating parent directories as needed.
+func Write(path string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := toml.NewEncoder(f).Encode(cfg); err != nil {
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package export serves collected metrics over HTTP, either as Prometheus
+// text exposition format (/metrics) or as JSON (/metrics.json), for the
+// -export flag, alongside the TUI or on its own with -headless.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zyoung11/TUI/internal/collector"
+)
+
+// Server polls a Collector on its own ticker and serves the latest
+// Snapshot to HTTP clients, independent of any Bubble Tea program.
+type Server struct {
+	collector *collector.Collector
+	interval  time.Duration
+
+	mu   sync.RWMutex
+	last collector.Snapshot
+}
+
+// NewServer returns a Server that samples c every interval.
+func NewServer(c *collector.Collector, interval time.Duration) *Server {
+	return &Server{collector: c, interval: interval}
+}
+
+// Serve polls in the background and blocks serving HTTP on addr (e.g.
+// ":9090") until the listener fails.
+func (s *Server) Serve(addr string) error {
+	go s.poll()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handlePrometheus)
+	mux.HandleFunc("/metrics.json", s.handleJSON)
+	return http.ListenAndServe(addr, mux)
+}
+
+func (s *Server) poll() {
+	snap, errs := s.collector.Collect()
+	for _, err := range errs {
+		log.Printf("collector: %v", err)
+	}
+	s.mu.Lock()
+	s.last = snap
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		snap, errs := s.collector.Collect()
+		for _, err := range errs {
+			log.Printf("collector: %v", err)
+		}
+		s.mu.Lock()
+		s.last = snap
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) snapshot() collector.Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+func (s *Server) handleJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handlePrometheus(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "# HELP tui_cpu_percent Total CPU utilization percentage.")
+	fmt.Fprintln(&b, "# TYPE tui_cpu_percent gauge")
+	fmt.Fprintf(&b, "tui_cpu_percent %f\n", snap.CPUTotal)
+
+	fmt.Fprintln(&b, "# HELP tui_cpu_core_percent Per-core CPU utilization percentage.")
+	fmt.Fprintln(&b, "# TYPE tui_cpu_core_percent gauge")
+	for i, pct := range snap.CPUPerCore {
+		fmt.Fprintf(&b, "tui_cpu_core_percent{core=\"%d\"} %f\n", i, pct)
+	}
+
+	fmt.Fprintln(&b, "# HELP tui_mem_used_percent Used memory percentage.")
+	fmt.Fprintln(&b, "# TYPE tui_mem_used_percent gauge")
+	fmt.Fprintf(&b, "tui_mem_used_percent %f\n", snap.MemPercent)
+
+	fmt.Fprintln(&b, "# HELP tui_disk_used_percent Used disk percentage per mountpoint.")
+	fmt.Fprintln(&b, "# TYPE tui_disk_used_percent gauge")
+	for _, d := range snap.Disks {
+		fmt.Fprintf(&b, "tui_disk_used_percent{mountpoint=%q} %f\n", d.Mountpoint, d.UsedPercent)
+	}
+
+	fmt.Fprintln(&b, "# HELP tui_disk_read_bytes_per_second Disk read rate per mountpoint.")
+	fmt.Fprintln(&b, "# TYPE tui_disk_read_bytes_per_second gauge")
+	for _, d := range snap.Disks {
+		fmt.Fprintf(&b, "tui_disk_read_bytes_per_second{mountpoint=%q} %f\n", d.Mountpoint, d.ReadBytes)
+	}
+
+	fmt.Fprintln(&b, "# HELP tui_disk_write_bytes_per_second Disk write rate per mountpoint.")
+	fmt.Fprintln(&b, "# TYPE tui_disk_write_bytes_per_second gauge")
+	for _, d := range snap.Disks {
+		fmt.Fprintf(&b, "tui_disk_write_bytes_per_second{mountpoint=%q} %f\n", d.Mountpoint, d.WriteBytes)
+	}
+
+	fmt.Fprintln(&b, "# HELP tui_net_recv_bytes_per_second Network receive rate per interface.")
+	fmt.Fprintln(&b, "# TYPE tui_net_recv_bytes_per_second gauge")
+	for _, n := range snap.Nets {
+		fmt.Fprintf(&b, "tui_net_recv_bytes_per_second{interface=%q} %f\n", n.Name, n.RecvBytes)
+	}
+
+	fmt.Fprintln(&b, "# HELP tui_net_sent_bytes_per_second Network send rate per interface.")
+	fmt.Fprintln(&b, "# TYPE tui_net_sent_bytes_per_second gauge")
+	for _, n := range snap.Nets {
+		fmt.Fprintf(&b, "tui_net_sent_bytes_per_second{interface=%q} %f\n", n.Name, n.SentBytes)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprint(w, b.String())
+}
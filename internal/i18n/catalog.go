@@ -0,0 +1,85 @@
+// Package i18n resolves user-facing dashboard strings through an embedded,
+// locale-keyed message catalog, so adding a language means adding a TOML
+// file rather than editing every call site.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed locales/*.toml
+var localesFS embed.FS
+
+const fallbackLocale = "en_US"
+
+// Catalog resolves message keys (e.g. "widget.cpu") to locale-specific
+// display strings.
+type Catalog struct {
+	locale   string
+	messages map[string]string
+	fallback map[string]string
+}
+
+// Load returns the Catalog for locale, falling back to fallbackLocale for
+// any key the requested locale doesn't define (and as the whole catalog if
+// locale itself isn't embedded).
+func Load(locale string) (Catalog, error) {
+	fallback, err := loadFile(fallbackLocale)
+	if err != nil {
+		return Catalog{}, err
+	}
+	if locale == "" || locale == fallbackLocale {
+		return Catalog{locale: fallbackLocale, messages: fallback, fallback: fallback}, nil
+	}
+
+	messages, err := loadFile(locale)
+	if err != nil {
+		return Catalog{locale: fallbackLocale, messages: fallback, fallback: fallback}, fmt.Errorf("locale %q: %w (using %s)", locale, err, fallbackLocale)
+	}
+	return Catalog{locale: locale, messages: messages, fallback: fallback}, nil
+}
+
+func loadFile(locale string) (map[string]string, error) {
+	data, err := localesFS.ReadFile("locales/" + locale + ".toml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown locale %q", locale)
+	}
+	var messages map[string]string
+	if _, err := toml.Decode(string(data), &messages); err != nil {
+		return nil, fmt.Errorf("parse locale %q: %w", locale, err)
+	}
+	return messages, nil
+}
+
+// Value resolves key to its display string in the catalog's locale, falling
+// back to fallbackLocale, then to the key itself so missing translations
+// degrade visibly rather than silently.
+func (c Catalog) Value(key string) string {
+	if v, ok := c.messages[key]; ok {
+		return v
+	}
+	if v, ok := c.fallback[key]; ok {
+		return v
+	}
+	return key
+}
+
+// Locales lists the embedded locale identifiers (e.g. "en_US", "zh_CN"),
+// sorted, for the --list=locales CLI flag.
+func Locales() []string {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		return nil
+	}
+	var locales []string
+	for _, e := range entries {
+		locales = append(locales, strings.TrimSuffix(e.Name(), ".toml"))
+	}
+	sort.Strings(locales)
+	return locales
+}
@@ -0,0 +1,188 @@
+// Package proctable implements a scrollable, sortable, filterable process
+// list widget backed by gopsutil/process, for embedding in the main
+// dashboard model.
+package proctable
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// SortColumn selects which field Rows are ordered by.
+type SortColumn int
+
+const (
+	SortCPU SortColumn = iota
+	SortMem
+	SortPID
+)
+
+// Row is a single rendered process line.
+type Row struct {
+	PID     int32
+	User    string
+	CPU     float64
+	RSSKiB  uint64
+	Command string
+}
+
+// Table holds the current process snapshot plus the view state (scroll
+// offset, sort column, filter query, selection) needed to render and
+// navigate it.
+type Table struct {
+	rows     []Row
+	filtered []Row
+
+	Sort   SortColumn
+	Filter string
+	Cursor int
+	Offset int
+
+	// Height is the number of rows visible at once; set from the
+	// surrounding layout on tea.WindowSizeMsg.
+	Height int
+}
+
+// New returns an empty Table ready to be populated by Refresh.
+func New() *Table {
+	return &Table{Sort: SortCPU, Height: 10}
+}
+
+// Refresh re-enumerates running processes and rebuilds the sorted, filtered
+// row set. Intended to be called once per tickMsg alongside the other
+// collectors.
+func (t *Table) Refresh() error {
+	procs, err := process.Processes()
+	if err != nil {
+		return fmt.Errorf("list processes: %w", err)
+	}
+
+	rows := make([]Row, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+		cpuPct, _ := p.CPUPercent()
+		user, _ := p.Username()
+		memInfo, _ := p.MemoryInfo()
+		var rss uint64
+		if memInfo != nil {
+			rss = memInfo.RSS / 1024
+		}
+		rows = append(rows, Row{
+			PID:     p.Pid,
+			User:    user,
+			CPU:     cpuPct,
+			RSSKiB:  rss,
+			Command: name,
+		})
+	}
+
+	t.rows = rows
+	t.applyFilterAndSort()
+	return nil
+}
+
+func (t *Table) applyFilterAndSort() {
+	base := t.rows
+	if t.Filter != "" {
+		names := make([]string, len(base))
+		for i, r := range base {
+			names[i] = r.Command
+		}
+		matches := fuzzy.Find(t.Filter, names)
+		matched := make([]Row, len(matches))
+		for i, match := range matches {
+			matched[i] = base[match.Index]
+		}
+		base = matched
+	}
+
+	sorted := make([]Row, len(base))
+	copy(sorted, base)
+	switch t.Sort {
+	case SortCPU:
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].CPU > sorted[j].CPU })
+	case SortMem:
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].RSSKiB > sorted[j].RSSKiB })
+	case SortPID:
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].PID < sorted[j].PID })
+	}
+	t.filtered = sorted
+
+	if t.Cursor >= len(t.filtered) {
+		t.Cursor = len(t.filtered) - 1
+	}
+	if t.Cursor < 0 {
+		t.Cursor = 0
+	}
+}
+
+// SetFilter updates the fuzzy filter query and re-derives the visible rows.
+func (t *Table) SetFilter(q string) {
+	t.Filter = q
+	t.applyFilterAndSort()
+}
+
+// SetSort changes the sort column and re-derives the visible rows.
+func (t *Table) SetSort(col SortColumn) {
+	t.Sort = col
+	t.applyFilterAndSort()
+}
+
+// MoveCursor shifts the selection by delta rows, clamping to the visible
+// range and scrolling Offset as needed.
+func (t *Table) MoveCursor(delta int) {
+	t.Cursor += delta
+	if t.Cursor >= len(t.filtered) {
+		t.Cursor = len(t.filtered) - 1
+	}
+	if t.Cursor < 0 {
+		t.Cursor = 0
+	}
+	if t.Cursor < t.Offset {
+		t.Offset = t.Cursor
+	}
+	if t.Height > 0 && t.Cursor >= t.Offset+t.Height {
+		t.Offset = t.Cursor - t.Height + 1
+	}
+}
+
+// Selected returns the row under the cursor, or false if the table is empty.
+func (t *Table) Selected() (Row, bool) {
+	if t.Cursor < 0 || t.Cursor >= len(t.filtered) {
+		return Row{}, false
+	}
+	return t.filtered[t.Cursor], true
+}
+
+// View renders the visible window of rows as a header plus Height data
+// lines, highlighting the selected row.
+func (t *Table) View(width int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-8s %-10s %6s %10s  %s\n", "PID", "USER", "CPU%", "RSS(KiB)", "COMMAND")
+
+	end := t.Offset + t.Height
+	if end > len(t.filtered) {
+		end = len(t.filtered)
+	}
+	for i := t.Offset; i < end; i++ {
+		r := t.filtered[i]
+		cursor := "  "
+		if i == t.Cursor {
+			cursor = "> "
+		}
+		line := fmt.Sprintf("%s%-8d %-10s %6.1f %10d  %s", cursor, r.PID, r.User, r.CPU, r.RSSKiB, r.Command)
+		if width > 0 && len(line) > width {
+			line = line[:width]
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package main
+
+import "strings"
+
+// brailleDots maps a sub-column (0 or 1) and sub-row (0=bottom..3=top)
+// within a single braille cell to the dot's bit in the Unicode braille
+// block (U+2800-U+28FF), so one rune encodes an 2x4 mini bar chart.
+var brailleDots = [4][2]rune{
+	{0x40, 0x80},
+	{0x04, 0x20},
+	{0x02, 0x10},
+	{0x01, 0x08},
+}
+
+// renderSparkline draws a braille line chart of samples across width
+// columns and height rows (each row packs 4 vertical sub-cells, so the
+// effective vertical resolution is height*4). Samples are expected in
+// [0, 1]; values outside are clamped. Only the most recent width*2 samples
+// are shown, right-aligned, mirroring how a live chart scrolls.
+func renderSparkline(data []float64, width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+
+	subRows := height * 4
+	subCols := width * 2
+
+	// filled[col] = number of sub-rows (from the bottom) that should be lit
+	// for that sub-column, or -1 if there is no sample yet.
+	filled := make([]int, subCols)
+	start := 0
+	if len(data) > subCols {
+		start = len(data) - subCols
+	}
+	visible := data[start:]
+	offset := subCols - len(visible)
+
+	for i := range filled {
+		if i < offset {
+			filled[i] = -1
+			continue
+		}
+		v := visible[i-offset]
+		if v < 0 {
+			v = 0
+		}
+		if v > 1 {
+			v = 1
+		}
+		filled[i] = int(v*float64(subRows) + 0.5)
+	}
+
+	var b strings.Builder
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			cell := rune(0x2800)
+			for subCol := 0; subCol < 2; subCol++ {
+				f := filled[col*2+subCol]
+				if f < 0 {
+					continue
+				}
+				for subRow := 0; subRow < 4; subRow++ {
+					// sub-rows are numbered bottom-to-top within the cell;
+					// "row" counts top-to-bottom across the whole chart.
+					heightFromBottom := (height-1-row)*4 + subRow
+					if heightFromBottom < f {
+						cell |= brailleDots[subRow][subCol]
+					}
+				}
+			}
+			b.WriteRune(cell)
+		}
+		if row != height-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}